@@ -0,0 +1,95 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"cmp"
+	"sort"
+	"strings"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Diff renders a table comparing two ResourceAccess snapshots, e.g. before and
+// after a Role change, or one user against another. Each cell shows the
+// access transition for that (group, resource, verb), so a gained or lost
+// verb stands out instead of having to eyeball two separate tables.
+func Diff(from, to ResourceAccess, verbs []string) *printer.Table {
+	names := make(map[string]struct{})
+	for name := range from {
+		names[name] = struct{}{}
+	}
+	for name := range to {
+		names[name] = struct{}{}
+	}
+
+	var groupResources []schema.GroupResource
+	for name := range names {
+		groupResources = append(groupResources, schema.ParseGroupResource(name))
+	}
+	sort.Slice(groupResources, func(i, j int) bool {
+		x := groupResources[i]
+		y := groupResources[j]
+		if x.Group != y.Group {
+			return cmp.Less(x.Group, y.Group)
+		}
+		return cmp.Less(x.Resource, y.Resource)
+	})
+
+	upperVerbs := make([]string, 0, len(verbs))
+	for _, v := range verbs {
+		upperVerbs = append(upperVerbs, strings.ToUpper(v))
+	}
+
+	p := printer.TableWithHeaders(nil)
+	p.Verbs = upperVerbs
+
+	lastGroup := ""
+	for i, gr := range groupResources {
+		displayGroup := gr.Group
+		if displayGroup == "" {
+			displayGroup = "core"
+		}
+
+		if gr.Group != lastGroup || i == 0 {
+			if i != 0 {
+				p.AddHeaderRow(printer.TextList(" "), printer.None)
+			}
+
+			groupRow := []printer.Renderable{printer.Styled(printer.RoleTitle, printer.Text(displayGroup+":"))}
+			for _, v := range upperVerbs {
+				groupRow = append(groupRow, printer.Styled(printer.RoleHeader, printer.Text(v)))
+			}
+			p.AddHeaderRow(groupRow, printer.None)
+			lastGroup = gr.Group
+		}
+
+		fromRes := from[gr.String()]
+		toRes := to[gr.String()]
+
+		var transitions []printer.Renderable
+		for _, v := range verbs {
+			transitions = append(transitions, printer.Transition{
+				From: outcomeFor(fromRes[v]),
+				To:   outcomeFor(toRes[v]),
+			})
+		}
+		p.AddRow(displayGroup, printer.TextList(gr.Resource), transitions...)
+	}
+	return p
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied},
+		"configmaps":       {"get": Allowed},
+		"deployments.apps": {"get": Allowed, "list": Allowed},
+	}
+	to := ResourceAccess{
+		"pods":             {"get": Denied, "list": Denied},
+		"deployments.apps": {"get": Allowed, "list": Allowed},
+		"jobs.batch":       {"get": Allowed},
+	}
+
+	p := Diff(from, to, []string{"get", "list"})
+
+	want := []tableRow{
+		{"core", "configmaps", []string{"yes->n/a", "n/a"}},
+		{"core", "pods", []string{"yes->no", "no"}},
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"batch", "jobs", []string{"n/a->yes", "n/a"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Diff() rows = %+v, want %+v", got, want)
+	}
+}
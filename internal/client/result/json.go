@@ -0,0 +1,147 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// outcomeName converts an Access value to the stable string used by the
+// machine-readable output formats. It intentionally differs from the
+// glyphs used by Table, since those are meant for terminals, not for
+// scripts that grep or jq the output.
+func outcomeName(a Access) string {
+	switch a {
+	case Denied:
+		return "denied"
+	case Allowed:
+		return "allowed"
+	case NotApplicable:
+		return "not_applicable"
+	case RequestErr:
+		return "error"
+	default:
+		return "not_applicable"
+	}
+}
+
+// accessFromOutcomeName is the inverse of outcomeName.
+func accessFromOutcomeName(name string) (Access, error) {
+	switch name {
+	case "denied":
+		return Denied, nil
+	case "allowed":
+		return Allowed, nil
+	case "not_applicable":
+		return NotApplicable, nil
+	case "error":
+		return RequestErr, nil
+	default:
+		return NotApplicable, fmt.Errorf("unknown access outcome %q", name)
+	}
+}
+
+// matrix flattens ra into group -> resource -> verb -> outcome, which is the
+// shape consumed by both JSON and YAML output.
+func (ra ResourceAccess) matrix(verbs []string) map[string]map[string]map[string]string {
+	matrix := make(map[string]map[string]map[string]string)
+
+	for name, verbAccess := range ra {
+		gr := schema.ParseGroupResource(name)
+
+		displayGroup := gr.Group
+		if displayGroup == "" {
+			displayGroup = "core"
+		}
+
+		if _, ok := matrix[displayGroup]; !ok {
+			matrix[displayGroup] = make(map[string]map[string]string)
+		}
+
+		outcomes := make(map[string]string, len(verbs))
+		for _, v := range verbs {
+			outcomes[v] = outcomeName(verbAccess[v])
+		}
+		matrix[displayGroup][gr.Resource] = outcomes
+	}
+
+	return matrix
+}
+
+// JSON renders the resource access matrix as group -> resource -> verb -> outcome,
+// so that it can be piped into jq, diffed across clusters, or consumed by CI scripts.
+func (ra ResourceAccess) JSON(verbs []string) ([]byte, error) {
+	return json.MarshalIndent(ra.matrix(verbs), "", "  ")
+}
+
+// YAML renders the resource access matrix in the same shape as JSON.
+func (ra ResourceAccess) YAML(verbs []string) ([]byte, error) {
+	return yaml.Marshal(ra.matrix(verbs))
+}
+
+// fromMatrix is the inverse of matrix: it rebuilds a ResourceAccess from the
+// group -> resource -> verb -> outcome shape written by JSON/YAML.
+func fromMatrix(matrix map[string]map[string]map[string]string) (ResourceAccess, error) {
+	ra := make(ResourceAccess)
+
+	for displayGroup, resources := range matrix {
+		group := displayGroup
+		if group == "core" {
+			group = ""
+		}
+
+		for resource, outcomes := range resources {
+			gr := schema.GroupResource{Group: group, Resource: resource}
+
+			verbAccess := make(map[string]Access, len(outcomes))
+			for verb, outcome := range outcomes {
+				a, err := accessFromOutcomeName(outcome)
+				if err != nil {
+					return nil, fmt.Errorf("%s verb %s: %w", gr, verb, err)
+				}
+				verbAccess[verb] = a
+			}
+			ra[gr.String()] = verbAccess
+		}
+	}
+
+	return ra, nil
+}
+
+// FromJSON parses a snapshot written by JSON back into a ResourceAccess, so
+// that e.g. `rakkess diff` can compare two previously saved snapshots instead
+// of only two in-memory results from the same run.
+func FromJSON(data []byte) (ResourceAccess, error) {
+	var matrix map[string]map[string]map[string]string
+	if err := json.Unmarshal(data, &matrix); err != nil {
+		return nil, err
+	}
+	return fromMatrix(matrix)
+}
+
+// FromYAML is the YAML equivalent of FromJSON.
+func FromYAML(data []byte) (ResourceAccess, error) {
+	var matrix map[string]map[string]map[string]string
+	if err := yaml.Unmarshal(data, &matrix); err != nil {
+		return nil, err
+	}
+	return fromMatrix(matrix)
+}
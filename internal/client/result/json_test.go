@@ -0,0 +1,80 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatrix(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied},
+		"deployments.apps": {"get": NotApplicable, "list": RequestErr},
+	}
+
+	want := map[string]map[string]map[string]string{
+		"core": {
+			"pods": {"get": "allowed", "list": "denied"},
+		},
+		"apps": {
+			"deployments": {"get": "not_applicable", "list": "error"},
+		},
+	}
+
+	if got := ra.matrix([]string{"get", "list"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("matrix() = %+v, want %+v", got, want)
+	}
+}
+
+func TestJSONYAMLRoundTrip(t *testing.T) {
+	ra := ResourceAccess{
+		"pods":             {"get": Allowed, "list": Denied},
+		"deployments.apps": {"get": NotApplicable, "list": RequestErr},
+	}
+	verbs := []string{"get", "list"}
+
+	tests := []struct {
+		name string
+		dump func(ResourceAccess, []string) ([]byte, error)
+		load func([]byte) (ResourceAccess, error)
+	}{
+		{"json", ResourceAccess.JSON, FromJSON},
+		{"yaml", ResourceAccess.YAML, FromYAML},
+	}
+
+	for _, test := range tests {
+		data, err := test.dump(ra, verbs)
+		if err != nil {
+			t.Fatalf("%s: dump failed: %v", test.name, err)
+		}
+		got, err := test.load(data)
+		if err != nil {
+			t.Fatalf("%s: load failed: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(got, ra) {
+			t.Errorf("%s: round trip = %+v, want %+v", test.name, got, ra)
+		}
+	}
+}
+
+func TestFromMatrixUnknownOutcome(t *testing.T) {
+	_, err := FromJSON([]byte(`{"core":{"pods":{"get":"bogus"}}}`))
+	if err == nil {
+		t.Error("FromJSON() with unknown outcome: want error, got nil")
+	}
+}
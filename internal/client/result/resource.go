@@ -18,6 +18,8 @@ package result
 
 import (
 	"cmp"
+	"fmt"
+	"path"
 	"sort"
 	"strings"
 
@@ -28,66 +30,169 @@ import (
 // ResourceAccess holds the access result for all resources.
 type ResourceAccess map[string]map[string]Access
 
-// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
-func (ra ResourceAccess) Table(verbs []string) *printer.Table {
-	var groupResources []schema.GroupResource
-	for name := range ra {
-		groupResources = append(groupResources, schema.ParseGroupResource(name))
-	}
-	sort.Slice(groupResources, func(i, j int) bool {
-		x := groupResources[i]
-		y := groupResources[j]
-		// first sort by group, then resource
-		if x.Group != y.Group {
-			return cmp.Less(x.Group, y.Group)
-		}
-		return cmp.Less(x.Resource, y.Resource)
-	})
+// TableOptions controls which rows ResourceAccess.Table renders and in what
+// order, so that large clusters with hundreds of CRDs stay readable.
+type TableOptions struct {
+	// HideDenied hides resources where every requested verb is denied or not applicable.
+	HideDenied bool
+	// OnlyAllowedGroups hides API groups that have no allowed verb for any of their resources.
+	OnlyAllowedGroups bool
+	// SortByAccess sorts resources within a group by descending number of
+	// allowed verbs instead of alphabetically.
+	SortByAccess bool
+	// Groups restricts output to API groups matching one of these globs
+	// (matched against the display name, so "core" matches the core group).
+	// An empty list means no filtering.
+	Groups []string
+}
+
+type resourceRow struct {
+	resource string
+	intro    []printer.Renderable
+	outcomes []printer.Renderable
+	allowed  int
+}
 
+// Print implements MatrixPrinter.Print. It prints a tab-separated table with a header.
+func (ra ResourceAccess) Table(verbs []string, opts TableOptions) (*printer.Table, error) {
 	upperVerbs := make([]string, 0, len(verbs))
 	for _, v := range verbs {
 		upperVerbs = append(upperVerbs, strings.ToUpper(v))
 	}
 
-	p := printer.TableWithHeaders(nil)
+	rowsByGroup := make(map[string][]resourceRow)
+	rawGroupOf := make(map[string]string)
+	var groups []string
 
-	// table body
-	lastGroup := ""
-	for i, gr := range groupResources {
-		// print the API group and verbs when the API group changes, or for the first API group (which often is "")
-		if gr.Group != lastGroup || i == 0 {
+	for name, res := range ra {
+		gr := schema.ParseGroupResource(name)
 
-			if i != 0 {
-				p.AddRow([]string{" "}, printer.None) // at least one "none" outcome needed to get the tabprinter aligning all columns
-			}
+		displayGroup := gr.Group
+		if displayGroup == "" {
+			displayGroup = "core"
+		}
+		matches, err := matchesAnyGroup(displayGroup, opts.Groups)
+		if err != nil {
+			return nil, err
+		}
+		if !matches {
+			continue
+		}
 
-			displayGroup := gr.Group
-			if displayGroup == "" {
-				displayGroup = "core"
+		var outcomes []printer.Renderable
+		allowed, onlyDeniedOrNA := 0, true
+		for _, v := range verbs {
+			o := outcomeFor(res[v])
+			if o == printer.Up {
+				allowed++
+			}
+			if o != printer.None && o != printer.Down {
+				onlyDeniedOrNA = false
 			}
+			outcomes = append(outcomes, o)
+		}
+		if opts.HideDenied && onlyDeniedOrNA {
+			continue
+		}
 
-			p.AddRow(append([]string{displayGroup + ":"}, upperVerbs...), printer.None)
-			lastGroup = gr.Group
+		if _, ok := rowsByGroup[displayGroup]; !ok {
+			groups = append(groups, displayGroup)
+			rawGroupOf[displayGroup] = gr.Group
 		}
+		rowsByGroup[displayGroup] = append(rowsByGroup[displayGroup], resourceRow{
+			resource: gr.Resource,
+			intro:    printer.TextList(gr.Resource),
+			outcomes: outcomes,
+			allowed:  allowed,
+		})
+	}
+	// Sort on the raw API group (empty string first), not the "core"
+	// substitution, so the core group always sorts first as it does in Diff().
+	sort.Slice(groups, func(i, j int) bool {
+		return cmp.Less(rawGroupOf[groups[i]], rawGroupOf[groups[j]])
+	})
 
-		var outcomes []printer.Outcome
+	p := printer.TableWithHeaders(nil)
+	p.Verbs = upperVerbs
 
-		res := ra[gr.String()]
-		for _, v := range verbs {
-			var o printer.Outcome
-			switch res[v] {
-			case Denied:
-				o = printer.Down
-			case Allowed:
-				o = printer.Up
-			case NotApplicable:
-				o = printer.None
-			case RequestErr:
-				o = printer.Err
-			}
-			outcomes = append(outcomes, o)
+	for i, group := range groups {
+		rows := rowsByGroup[group]
+
+		if opts.OnlyAllowedGroups && !anyAllowed(rows) {
+			continue
+		}
+
+		if opts.SortByAccess {
+			sort.SliceStable(rows, func(a, b int) bool {
+				if rows[a].allowed != rows[b].allowed {
+					return rows[a].allowed > rows[b].allowed
+				}
+				return cmp.Less(rows[a].resource, rows[b].resource)
+			})
+		} else {
+			sort.Slice(rows, func(a, b int) bool {
+				return cmp.Less(rows[a].resource, rows[b].resource)
+			})
+		}
+
+		if i != 0 {
+			p.AddHeaderRow(printer.TextList(" "), printer.None) // at least one "none" outcome needed to get the tabprinter aligning all columns
 		}
-		p.AddRow([]string{gr.Resource}, outcomes...)
+
+		groupRow := []printer.Renderable{printer.Styled(printer.RoleTitle, printer.Text(group+":"))}
+		for _, v := range upperVerbs {
+			groupRow = append(groupRow, printer.Styled(printer.RoleHeader, printer.Text(v)))
+		}
+		p.AddHeaderRow(groupRow, printer.None)
+
+		for _, row := range rows {
+			p.AddRow(group, row.intro, row.outcomes...)
+		}
+	}
+	return p, nil
+}
+
+// anyAllowed reports whether any row has at least one allowed verb.
+func anyAllowed(rows []resourceRow) bool {
+	for _, row := range rows {
+		if row.allowed > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyGroup reports whether group matches one of the given globs.
+// An empty glob list matches everything. It returns an error if any glob is
+// malformed, so a typo in e.g. --groups doesn't silently render an empty table.
+func matchesAnyGroup(group string, globs []string) (bool, error) {
+	if len(globs) == 0 {
+		return true, nil
+	}
+	for _, g := range globs {
+		ok, err := path.Match(g, group)
+		if err != nil {
+			return false, fmt.Errorf("invalid group filter %q: %w", g, err)
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// outcomeFor converts a raw Access result into the Outcome used for rendering.
+func outcomeFor(a Access) printer.Outcome {
+	switch a {
+	case Denied:
+		return printer.Down
+	case Allowed:
+		return printer.Up
+	case NotApplicable:
+		return printer.None
+	case RequestErr:
+		return printer.Err
+	default:
+		return printer.None
 	}
-	return p
 }
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package result
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/corneliusweig/rakkess/internal/printer"
+)
+
+// testResourceAccess has a handful of resources spread across the core,
+// apps and batch groups with varying amounts of access, enough to exercise
+// every TableOptions knob.
+func testResourceAccess() ResourceAccess {
+	return ResourceAccess{
+		"pods":              {"get": Allowed, "list": Denied},       // core, allowed=1
+		"configmaps":        {"get": Denied, "list": Denied},        // core, allowed=0
+		"deployments.apps":  {"get": Allowed, "list": Allowed},      // apps, allowed=2
+		"statefulsets.apps": {"get": Allowed, "list": Denied},       // apps, allowed=1
+		"replicasets.apps":  {"get": Denied, "list": NotApplicable}, // apps, allowed=0
+		"jobs.batch":        {"get": Denied, "list": NotApplicable}, // batch, allowed=0
+	}
+}
+
+// tableRow is a flattened, render-independent view of a data row, used so
+// tests can assert on group/resource/outcome without depending on the
+// tabwriter layout.
+type tableRow struct {
+	group    string
+	resource string
+	outcomes []string
+}
+
+func tableRows(p *printer.Table) []tableRow {
+	env := printer.Env{OutputFormat: "ascii-table"}
+
+	var rows []tableRow
+	for _, r := range p.Rows {
+		if r.Header {
+			continue
+		}
+		var resource string
+		for _, intro := range r.Intro {
+			resource += intro.Render(env)
+		}
+		var outcomes []string
+		for _, e := range r.Entries {
+			outcomes = append(outcomes, e.Render(env))
+		}
+		rows = append(rows, tableRow{group: r.Group, resource: resource, outcomes: outcomes})
+	}
+	return rows
+}
+
+func TestTableDefaultOrder(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	want := []tableRow{
+		{"core", "configmaps", []string{"no", "no"}},
+		{"core", "pods", []string{"yes", "no"}},
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"apps", "replicasets", []string{"no", "n/a"}},
+		{"apps", "statefulsets", []string{"yes", "no"}},
+		{"batch", "jobs", []string{"no", "n/a"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Table() rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableHideDenied(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{HideDenied: true})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	want := []tableRow{
+		{"core", "pods", []string{"yes", "no"}},
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"apps", "statefulsets", []string{"yes", "no"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Table(HideDenied) rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableOnlyAllowedGroups(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{OnlyAllowedGroups: true})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	for _, row := range tableRows(p) {
+		if row.group == "batch" {
+			t.Errorf("Table(OnlyAllowedGroups) kept batch group, which has no allowed verbs: %+v", row)
+		}
+	}
+}
+
+func TestTableSortByAccess(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{SortByAccess: true})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	want := []tableRow{
+		{"core", "pods", []string{"yes", "no"}},
+		{"core", "configmaps", []string{"no", "no"}},
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"apps", "statefulsets", []string{"yes", "no"}},
+		{"apps", "replicasets", []string{"no", "n/a"}},
+		{"batch", "jobs", []string{"no", "n/a"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Table(SortByAccess) rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableGroups(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{Groups: []string{"apps"}})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	want := []tableRow{
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"apps", "replicasets", []string{"no", "n/a"}},
+		{"apps", "statefulsets", []string{"yes", "no"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Table(Groups) rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableHideDeniedSortByAccessAndGroupsCombined(t *testing.T) {
+	ra := testResourceAccess()
+	p, err := ra.Table([]string{"get", "list"}, TableOptions{
+		HideDenied:   true,
+		SortByAccess: true,
+		Groups:       []string{"apps"},
+	})
+	if err != nil {
+		t.Fatalf("Table() error = %v", err)
+	}
+
+	want := []tableRow{
+		{"apps", "deployments", []string{"yes", "yes"}},
+		{"apps", "statefulsets", []string{"yes", "no"}},
+	}
+	if got := tableRows(p); !reflect.DeepEqual(got, want) {
+		t.Errorf("Table(HideDenied+SortByAccess+Groups) rows = %+v, want %+v", got, want)
+	}
+}
+
+func TestTableInvalidGroupGlob(t *testing.T) {
+	ra := testResourceAccess()
+	_, err := ra.Table([]string{"get", "list"}, TableOptions{Groups: []string{"["}})
+	if err == nil {
+		t.Error("Table() with malformed --groups glob: want error, got nil")
+	}
+}
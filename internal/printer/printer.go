@@ -17,27 +17,133 @@ limitations under the License.
 package printer
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"html"
 	"io"
+	"os"
 	"sync"
 
 	"github.com/corneliusweig/tabwriter"
-)
-
-type color int
-
-const (
-	red    = color(31)
-	green  = color(32)
-	purple = color(35)
-	none   = color(0)
+	"github.com/juju/ansiterm"
 )
 
 var (
 	isTerminal = isTerminalImpl
 	once       sync.Once
+
+	// ForceNoColor lets callers (e.g. a --no-color flag) disable color
+	// regardless of terminal detection.
+	ForceNoColor = false
+
+	// backend is the color scheme used to render semantic Roles. It is a
+	// package variable rather than a hard dependency so that the ANSI
+	// implementation below can be swapped out, e.g. in tests.
+	backend ColorBackend = ansiBackend{}
+)
+
+// noColor reports whether color output was explicitly disabled, either via
+// ForceNoColor or the NO_COLOR environment variable (see https://no-color.org).
+func noColor() bool {
+	if ForceNoColor {
+		return true
+	}
+	_, ok := os.LookupEnv("NO_COLOR")
+	return ok
+}
+
+// Role is a semantic color role. Keeping callers in terms of roles instead of
+// concrete colors means the color scheme can change without touching every
+// call site that wants a title, a header, or an error to stand out.
+type Role int
+
+const (
+	RoleTitle Role = iota
+	RoleHeader
+	RoleSuccess
+	RoleFailure
+	RoleError
+	RoleMuted
 )
 
+// ColorBackend renders a string styled for the given Role.
+type ColorBackend interface {
+	Style(role Role, s string) string
+	// Bold renders s in bold, independent of any Role coloring.
+	Bold(s string) string
+}
+
+// ansiBackend renders Roles via github.com/juju/ansiterm, the same library
+// used elsewhere for tabwriter-compatible colored terminal output.
+type ansiBackend struct{}
+
+func (ansiBackend) roleColor(role Role) ansiterm.Color {
+	switch role {
+	case RoleTitle:
+		return ansiterm.Cyan
+	case RoleHeader:
+		return ansiterm.Blue
+	case RoleSuccess:
+		return ansiterm.Green
+	case RoleFailure:
+		return ansiterm.Red
+	case RoleError:
+		return ansiterm.Magenta
+	case RoleMuted:
+		return ansiterm.DarkGray
+	default:
+		return ansiterm.Default
+	}
+}
+
+func (b ansiBackend) Style(role Role, s string) string {
+	var buf bytes.Buffer
+	w := ansiterm.NewWriter(&buf)
+	w.SetColorCapable(true) // the color/no-color decision was already made by the caller
+
+	// corneliusweig/tabwriter needs each escape sequence individually
+	// bracketed with Escape so it can exclude it from column width
+	// calculations; ansiterm only knows how to write the raw SGR codes.
+	buf.WriteByte(tabwriter.Escape)
+	w.SetForeground(b.roleColor(role))
+	buf.WriteByte(tabwriter.Escape)
+	buf.WriteString(s)
+	buf.WriteByte(tabwriter.Escape)
+	w.Reset()
+	buf.WriteByte(tabwriter.Escape)
+
+	return buf.String()
+}
+
+func (ansiBackend) Bold(s string) string {
+	var buf bytes.Buffer
+	w := ansiterm.NewWriter(&buf)
+	w.SetColorCapable(true) // the color/no-color decision was already made by the caller
+
+	buf.WriteByte(tabwriter.Escape)
+	w.SetStyle(ansiterm.Bold)
+	buf.WriteByte(tabwriter.Escape)
+	buf.WriteString(s)
+	buf.WriteByte(tabwriter.Escape)
+	w.Reset()
+	buf.WriteByte(tabwriter.Escape)
+
+	return buf.String()
+}
+
+// Styled wraps r so that it is rendered in the given Role's color whenever
+// the output environment supports color.
+func Styled(role Role, r Renderable) Renderable {
+	return RenderableFunc(func(e Env) string {
+		inner := r.Render(e)
+		if !e.Color {
+			return inner
+		}
+		return backend.Style(role, inner)
+	})
+}
+
 type Outcome uint8
 
 const (
@@ -48,8 +154,11 @@ const (
 )
 
 func (o Outcome) Render(e Env) string {
+	if e.OutputFormat == "html" {
+		return htmlAccessCode(o)
+	}
 	conv := humanreadableAccessCode
-	if e.IsTerminal {
+	if e.Color {
 		conv = colored(conv)
 	}
 	if e.OutputFormat == "ascii-table" {
@@ -61,6 +170,9 @@ func (o Outcome) Render(e Env) string {
 type Env struct {
 	IsTerminal   bool
 	OutputFormat string
+	// Color reports whether escape codes should be emitted. It is derived
+	// from IsTerminal, but additionally honors NO_COLOR and ForceNoColor.
+	Color bool
 }
 
 type Renderable interface {
@@ -74,12 +186,24 @@ func (t Text) Render(_ Env) string {
 }
 
 type Row struct {
-	Intro   []Renderable
-	Entries []Outcome
+	Intro []Renderable
+	// Entries are usually Outcomes, but anything Renderable works, e.g. a
+	// Transition for a diff table.
+	Entries []Renderable
+	// Group is the API group this row belongs to. It is empty for Header rows.
+	Group string
+	// Header marks a row as decoration (a group separator or group/verb label)
+	// rather than data. Machine-readable formats like CSV skip these rows.
+	Header bool
 }
 type Table struct {
 	Headers []Renderable
 	Rows    []Row
+	// Verbs are the verb column names, in column order. Unlike Headers, these
+	// are not rendered inline in the terminal/ascii-table formats (which show
+	// the verbs per API group instead), but are used as the header row for
+	// machine-readable formats such as CSV.
+	Verbs []string
 }
 
 func TableWithHeaders(headers []Renderable) *Table {
@@ -88,23 +212,38 @@ func TableWithHeaders(headers []Renderable) *Table {
 	}
 }
 
-func (p *Table) AddRow(intro []Renderable, outcomes ...Outcome) {
-	row := Row{
-		Intro:   intro,
-		Entries: outcomes,
-	}
-	p.Rows = append(p.Rows, row)
+// AddHeaderRow adds a decorative row, such as a group separator or a
+// group/verb label row. Header rows are rendered in the terminal/ascii-table
+// formats but are skipped by machine-readable formats like CSV.
+func (p *Table) AddHeaderRow(intro []Renderable, entries ...Renderable) {
+	p.Rows = append(p.Rows, Row{Intro: intro, Entries: entries, Header: true})
+}
+
+// AddRow adds a data row for the given API group.
+func (p *Table) AddRow(group string, intro []Renderable, entries ...Renderable) {
+	p.Rows = append(p.Rows, Row{Group: group, Intro: intro, Entries: entries})
 }
 
 func (p *Table) Render(out io.Writer, outputFormat string) {
+	if outputFormat == "csv" {
+		p.renderCSV(out)
+		return
+	}
+	if outputFormat == "html" {
+		p.renderHTML(out)
+		return
+	}
+
 	once.Do(func() { initTerminal(out) })
 
 	w := tabwriter.NewWriter(out, 4, 8, 2, ' ', tabwriter.SmashEscape|tabwriter.StripEscape)
 	defer w.Flush()
 
+	terminal := isTerminal(out)
 	env := Env{
-		IsTerminal:   isTerminal(out),
+		IsTerminal:   terminal,
 		OutputFormat: outputFormat,
+		Color:        terminal && !noColor(),
 	}
 
 	// table header
@@ -133,6 +272,36 @@ func (p *Table) Render(out io.Writer, outputFormat string) {
 	}
 }
 
+// renderCSV emits the table as RFC 4180 CSV: one header row, then one row per
+// (group, resource) pair and one column per verb. Unlike the tabwriter path,
+// it never emits color escapes and drops the group-separator rows that the
+// terminal/ascii-table formats use for readability.
+func (p *Table) renderCSV(out io.Writer) {
+	env := Env{OutputFormat: "ascii-table"} // plain yes/no/n/a/ERR cells, never colored
+
+	w := csv.NewWriter(out)
+	defer w.Flush()
+
+	header := append([]string{"GROUP", "RESOURCE"}, p.Verbs...)
+	w.Write(header)
+
+	for _, row := range p.Rows {
+		if row.Header {
+			continue
+		}
+
+		record := make([]string, 0, len(header))
+		record = append(record, row.Group)
+		for _, intro := range row.Intro {
+			record = append(record, intro.Render(env))
+		}
+		for _, e := range row.Entries {
+			record = append(record, e.Render(env))
+		}
+		w.Write(record)
+	}
+}
+
 func humanreadableAccessCode(o Outcome) string {
 	switch o {
 	case None:
@@ -150,17 +319,144 @@ func humanreadableAccessCode(o Outcome) string {
 
 func colored(wrap func(Outcome) string) func(Outcome) string {
 	return func(o Outcome) string {
-		c := none
+		role := RoleMuted
 		switch o {
 		case Up:
-			c = green
+			role = RoleSuccess
 		case Down:
-			c = red
+			role = RoleFailure
 		case Err:
-			c = purple
+			role = RoleError
+		case None:
+			return wrap(o)
+		}
+		return backend.Style(role, wrap(o))
+	}
+}
+
+// htmlAccessCode renders o as a <span> carrying a CSS class instead of an
+// ANSI escape, so a stylesheet drives the color instead of inline codes.
+func htmlAccessCode(o Outcome) string {
+	class, symbol := "na", "–"
+	switch o {
+	case Up:
+		class, symbol = "allowed", "✔"
+	case Down:
+		class, symbol = "denied", "✖"
+	case Err:
+		class, symbol = "err", "ERR"
+	}
+	return fmt.Sprintf(`<span class="%s">%s</span>`, class, symbol)
+}
+
+const htmlDocHeader = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>rakkess report</title>
+<style>
+body { font-family: sans-serif; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { padding: 2px 8px; text-align: left; }
+summary { font-weight: bold; cursor: pointer; }
+.allowed { color: green; }
+.denied { color: firebrick; }
+.err { color: purple; }
+.na { color: gray; }
+</style>
+</head>
+<body>
+`
+
+const htmlDocFooter = `</body>
+</html>
+`
+
+// renderHTML emits the table as a standalone HTML document with one collapsible
+// <details> section per API group, and CSS classes driving the cell color
+// instead of ANSI escapes.
+func (p *Table) renderHTML(out io.Writer) {
+	env := Env{OutputFormat: "html"}
+
+	fmt.Fprint(out, htmlDocHeader)
+
+	group, open := "", false
+	for _, row := range p.Rows {
+		if row.Header {
+			continue
 		}
-		return fmt.Sprintf("\xff\033[%dm\xff%s\xff\033[0m\xff", c, wrap(o))
+
+		if !open || row.Group != group {
+			if open {
+				fmt.Fprint(out, "</tbody></table></details>\n")
+			}
+			group, open = row.Group, true
+
+			fmt.Fprintf(out, "<details open><summary>%s</summary><table><thead><tr><th>Resource</th>", html.EscapeString(group))
+			for _, v := range p.Verbs {
+				fmt.Fprintf(out, "<th>%s</th>", html.EscapeString(v))
+			}
+			fmt.Fprint(out, "</tr></thead><tbody>\n")
+		}
+
+		fmt.Fprint(out, "<tr>")
+		for _, intro := range row.Intro {
+			fmt.Fprintf(out, "<td>%s</td>", html.EscapeString(intro.Render(env)))
+		}
+		for _, e := range row.Entries {
+			fmt.Fprintf(out, "<td>%s</td>", e.Render(env))
+		}
+		fmt.Fprint(out, "</tr>\n")
+	}
+	if open {
+		fmt.Fprint(out, "</tbody></table></details>\n")
+	}
+
+	fmt.Fprint(out, htmlDocFooter)
+}
+
+// Transition renders the change between two Outcomes for the same
+// (group, resource, verb), e.g. when comparing access before/after a Role
+// change, or one user against another. Unchanged outcomes render as the plain,
+// uncolored Outcome glyph; changed ones render as "before->after" (or
+// "before→after" outside the plain-ascii format), with the whole cell colored
+// by a single Role (success for gained, failure for lost, error if either side
+// errored, muted otherwise) so gained/lost access stands out.
+type Transition struct {
+	From, To Outcome
+}
+
+func (t Transition) Render(e Env) string {
+	// Render the individual glyphs without their usual per-outcome color: the
+	// whole transition cell gets at most one color below, picked for the
+	// transition as a whole instead of recoloring each side independently.
+	plain := e
+	plain.Color = false
+
+	if t.From == t.To {
+		return t.To.Render(plain)
+	}
+
+	arrow := "->"
+	if e.OutputFormat != "ascii-table" {
+		arrow = "→"
+	}
+	s := t.From.Render(plain) + arrow + t.To.Render(plain)
+
+	if !e.Color {
+		return s
+	}
+
+	role := RoleMuted
+	switch {
+	case t.From == Err || t.To == Err:
+		role = RoleError
+	case t.To == Up:
+		role = RoleSuccess // gained access
+	case t.From == Up:
+		role = RoleFailure // lost access
 	}
+	return backend.Style(role, s)
 }
 
 func asciiAccessCode(o Outcome) string {
@@ -181,10 +477,10 @@ func asciiAccessCode(o Outcome) string {
 func Bold(in Renderable) Renderable {
 	return RenderableFunc(func(e Env) string {
 		inner := in.Render(e)
-		if !e.IsTerminal {
+		if !e.Color {
 			return inner
 		}
-		return fmt.Sprintf("\xff\033[1m%s\033[0m\xff", inner)
+		return backend.Bold(inner)
 	})
 }
 
@@ -0,0 +1,86 @@
+/*
+Copyright 2020 Cornelius Weig
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package printer
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestHtmlAccessCode(t *testing.T) {
+	tests := []struct {
+		outcome Outcome
+		want    string
+	}{
+		{None, `<span class="na">–</span>`},
+		{Up, `<span class="allowed">✔</span>`},
+		{Down, `<span class="denied">✖</span>`},
+		{Err, `<span class="err">ERR</span>`},
+	}
+	for _, test := range tests {
+		if got := htmlAccessCode(test.outcome); got != test.want {
+			t.Errorf("htmlAccessCode(%v) = %q, want %q", test.outcome, got, test.want)
+		}
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	p := TableWithHeaders(nil)
+	p.Verbs = []string{"GET", "LIST"}
+	p.AddHeaderRow(TextList(" "), None) // group separator rows must be skipped
+	p.AddRow("apps", TextList(`widgets, "fancy"`), Up, Down)
+
+	var buf bytes.Buffer
+	p.renderCSV(&buf)
+
+	want := "GROUP,RESOURCE,GET,LIST\napps,\"widgets, \"\"fancy\"\"\",yes,no\n"
+	if got := buf.String(); got != want {
+		t.Errorf("renderCSV() = %q, want %q", got, want)
+	}
+}
+
+func TestTransitionRender(t *testing.T) {
+	env := Env{IsTerminal: true, Color: true}
+
+	tests := []struct {
+		name       string
+		transition Transition
+		wantRole   Role
+		wantColor  bool
+	}{
+		{"gained", Transition{From: Down, To: Up}, RoleSuccess, true},
+		{"lost", Transition{From: Up, To: Down}, RoleFailure, true},
+		{"unchanged allowed", Transition{From: Up, To: Up}, 0, false},
+		{"unchanged denied", Transition{From: Down, To: Down}, 0, false},
+		{"errored", Transition{From: Up, To: Err}, RoleError, true},
+	}
+
+	for _, test := range tests {
+		got := test.transition.Render(env)
+		want := test.transition.From.Render(Env{}) // plain, uncolored glyph(s)
+		if test.transition.From != test.transition.To {
+			arrow := "→"
+			want = test.transition.From.Render(Env{}) + arrow + test.transition.To.Render(Env{})
+		}
+		if test.wantColor {
+			want = backend.Style(test.wantRole, want)
+		}
+		if got != want {
+			t.Errorf("%s: Transition.Render() = %q, want %q", test.name, got, want)
+		}
+	}
+}